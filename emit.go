@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: MIT
+package jtl
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Emitter renders a parsed JTL tree, as produced by Parse, to an output
+// format.
+type Emitter interface {
+	Emit(w io.Writer, elements []interface{}) error
+}
+
+// EmitterFunc adapts a plain emit function to the Emitter interface.
+type EmitterFunc func(w io.Writer, elements []interface{}) error
+
+// Emit calls f(w, elements).
+func (f EmitterFunc) Emit(w io.Writer, elements []interface{}) error {
+	return f(w, elements)
+}
+
+// HTMLEmitter, XMLEmitter, and JTLEmitter are the Emitter values for
+// EmitHTML, EmitXML, and EmitJTL, for callers that want an Emitter
+// rather than a bare function.
+var (
+	HTMLEmitter Emitter = EmitterFunc(EmitHTML)
+	XMLEmitter  Emitter = EmitterFunc(EmitXML)
+	JTLEmitter  Emitter = EmitterFunc(EmitJTL)
+)
+
+// Schema describes which attributes are required on each tag before
+// emission, keyed by an element's "tag" attribute.
+type Schema map[string][]string
+
+// Validate checks that every element, and its descendants, carries the
+// attributes Schema requires for its "tag", returning the first
+// violation found.
+func Validate(schema Schema, elements []interface{}) error {
+	for _, node := range elements {
+		elem, ok := node.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("jtl: unexpected node type %T", node)
+		}
+
+		tag, _ := elem["tag"].(string)
+		for _, required := range schema[tag] {
+			if _, ok := elem[required]; !ok {
+				return fmt.Errorf("jtl: element %q missing required attribute %q for tag %q", elem["KEY"], required, tag)
+			}
+		}
+
+		if children, ok := elem["children"].([]interface{}); ok {
+			if err := Validate(schema, children); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var validTagRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+// sanitizeTag returns tag if it is safe to write verbatim as an
+// HTML/XML element name, and fallback otherwise. A JTL attribute value
+// can contain anything except '"' and '>', so an unvalidated "tag" attribute
+// (e.g. `tag="script onerror=alert(1)"`) would otherwise let a document
+// break out of the element name position.
+func sanitizeTag(tag, fallback string) string {
+	if validTagRe.MatchString(tag) {
+		return tag
+	}
+	return fallback
+}
+
+// attrKeys returns elem's emittable attribute keys, excluding the
+// bookkeeping keys Parse always sets, in a stable order.
+func attrKeys(elem map[string]interface{}) []string {
+	var keys []string
+	for key := range elem {
+		switch key {
+		case "KEY", "Content", "Contents", "children", "tag":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EmitHTML renders elements as HTML: each element's "tag" attribute
+// becomes the HTML element name (falling back to "div"), its other
+// attributes become HTML attributes, Content becomes the text node, and
+// children recurse.
+func EmitHTML(w io.Writer, elements []interface{}) error {
+	for _, node := range elements {
+		if err := emitHTMLElement(w, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func emitHTMLElement(w io.Writer, node interface{}) error {
+	elem, ok := node.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("jtl: unexpected node type %T", node)
+	}
+
+	tag, _ := elem["tag"].(string)
+	tag = sanitizeTag(tag, "div")
+
+	if _, err := fmt.Fprintf(w, "<%s", tag); err != nil {
+		return err
+	}
+	for _, key := range attrKeys(elem) {
+		value, ok := elem[key].(string)
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, ` %s="%s"`, key, html.EscapeString(value)); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, ">"); err != nil {
+		return err
+	}
+
+	if content, _ := elem["Content"].(string); content != "" {
+		if _, err := io.WriteString(w, html.EscapeString(content)); err != nil {
+			return err
+		}
+	}
+
+	if children, ok := elem["children"].([]interface{}); ok {
+		for _, child := range children {
+			if err := emitHTMLElement(w, child); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "</%s>", tag)
+	return err
+}
+
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+)
+
+// xmlWriter writes XML incrementally by hand, in the style of the small
+// forked XML encoders used internally by packages like x/net/webdav,
+// rather than pulling in encoding/xml for a document shape this simple.
+type xmlWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (xw *xmlWriter) startElement(name string, attrs map[string]string, keys []string) {
+	if xw.err != nil {
+		return
+	}
+	if _, err := fmt.Fprintf(xw.w, "<%s", name); err != nil {
+		xw.err = err
+		return
+	}
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(xw.w, ` %s="%s"`, key, xmlEscaper.Replace(attrs[key])); err != nil {
+			xw.err = err
+			return
+		}
+	}
+	_, xw.err = io.WriteString(xw.w, ">")
+}
+
+func (xw *xmlWriter) charData(s string) {
+	if xw.err != nil || s == "" {
+		return
+	}
+	_, xw.err = io.WriteString(xw.w, xmlEscaper.Replace(s))
+}
+
+func (xw *xmlWriter) endElement(name string) {
+	if xw.err != nil {
+		return
+	}
+	_, xw.err = fmt.Fprintf(xw.w, "</%s>", name)
+}
+
+// EmitXML renders elements as XML so that JTL's semi-colon-terminated
+// bracketed content survives round-tripping: each element's "tag"
+// attribute becomes the XML element name (falling back to "elem"), its
+// other attributes become XML attributes, Content becomes char data, and
+// children recurse.
+func EmitXML(w io.Writer, elements []interface{}) error {
+	xw := &xmlWriter{w: w}
+	for _, node := range elements {
+		emitXMLElement(xw, node)
+		if xw.err != nil {
+			return xw.err
+		}
+	}
+	return xw.err
+}
+
+func emitXMLElement(xw *xmlWriter, node interface{}) {
+	if xw.err != nil {
+		return
+	}
+	elem, ok := node.(map[string]interface{})
+	if !ok {
+		xw.err = fmt.Errorf("jtl: unexpected node type %T", node)
+		return
+	}
+
+	tag, _ := elem["tag"].(string)
+	tag = sanitizeTag(tag, "elem")
+
+	attrs := make(map[string]string)
+	keys := attrKeys(elem)
+	for _, key := range keys {
+		if value, ok := elem[key].(string); ok {
+			attrs[key] = value
+		}
+	}
+
+	xw.startElement(tag, attrs, keys)
+	if content, _ := elem["Content"].(string); content != "" {
+		xw.charData(content)
+	}
+	if children, ok := elem["children"].([]interface{}); ok {
+		for _, child := range children {
+			emitXMLElement(xw, child)
+		}
+	}
+	xw.endElement(tag)
+}
+
+// EmitJTL re-serializes elements as JTL text via Encoder, giving
+// EmitHTML and EmitXML a round-trip counterpart. Encode recurses into
+// each element's children itself, so only the roots are passed in here.
+func EmitJTL(w io.Writer, elements []interface{}) error {
+	enc := NewEncoder(w)
+	for _, node := range elements {
+		elem, ok := node.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("jtl: unexpected node type %T", node)
+		}
+		if err := enc.Encode(elem); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
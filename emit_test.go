@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+package jtl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OrtheSnowJames/jtl"
+)
+
+func TestEmitHTML(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" tag="span">greeting>Hello, World!;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	if err := jtl.EmitHTML(&buf, parsed); err != nil {
+		t.Fatalf("EmitHTML() error = %v", err)
+	}
+
+	want := `<span class="main">Hello, World!</span>`
+	if got := buf.String(); got != want {
+		t.Errorf("EmitHTML() = %q, want %q", got, want)
+	}
+}
+
+func TestEmitXML(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" tag="span">greeting>Hello, World!;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	if err := jtl.EmitXML(&buf, parsed); err != nil {
+		t.Fatalf("EmitXML() error = %v", err)
+	}
+
+	want := `<span class="main">Hello, World!</span>`
+	if got := buf.String(); got != want {
+		t.Errorf("EmitXML() = %q, want %q", got, want)
+	}
+}
+
+func TestEmitHTMLSanitizesInvalidTag(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >tag="script onerror=alert(1)">payload>hi;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	if err := jtl.EmitHTML(&buf, parsed); err != nil {
+		t.Fatalf("EmitHTML() error = %v", err)
+	}
+
+	want := `<div>hi</div>`
+	if got := buf.String(); got != want {
+		t.Errorf("EmitHTML() = %q, want %q (invalid tag must fall back to div)", got, want)
+	}
+}
+
+func TestEmitXMLSanitizesInvalidTag(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >tag="elem onclick=alert(1)">payload>hi;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	if err := jtl.EmitXML(&buf, parsed); err != nil {
+		t.Fatalf("EmitXML() error = %v", err)
+	}
+
+	want := `<elem>hi</elem>`
+	if got := buf.String(); got != want {
+		t.Errorf("EmitXML() = %q, want %q (invalid tag must fall back to elem)", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >tag="span">greeting>Hello;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	schema := jtl.Schema{"span": {"class"}}
+	if err := jtl.Validate(schema, parsed); err == nil {
+		t.Error("Validate() error = nil, want missing attribute error")
+	}
+
+	schema = jtl.Schema{"span": {"tag"}}
+	if err := jtl.Validate(schema, parsed); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MIT
+package jtl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HelperFunc implements a named template helper invoked from JTL content
+// using the {{name arg1 "arg2"}} syntax.
+type HelperFunc func(args []string, ctx map[string]interface{}) (string, error)
+
+// Registry holds named helpers. Parse resolves element content against
+// DefaultRegistry only; other registries are not consulted.
+type Registry struct {
+	helpers map[string]HelperFunc
+}
+
+// NewRegistry creates an empty helper registry.
+func NewRegistry() *Registry {
+	return &Registry{helpers: make(map[string]HelperFunc)}
+}
+
+// Register adds fn under name to r, replacing any existing helper of the
+// same name.
+func (r *Registry) Register(name string, fn HelperFunc) {
+	r.helpers[name] = fn
+}
+
+// Remove deletes name from r.
+func (r *Registry) Remove(name string) {
+	delete(r.helpers, name)
+}
+
+// DefaultRegistry is the global registry consulted while resolving
+// element content during Parse.
+var DefaultRegistry = NewRegistry()
+
+// RegisterHelper adds fn under name to DefaultRegistry.
+func RegisterHelper(name string, fn HelperFunc) {
+	DefaultRegistry.Register(name, fn)
+}
+
+// RemoveHelper removes name from DefaultRegistry.
+func RemoveHelper(name string) {
+	DefaultRegistry.Remove(name)
+}
+
+var (
+	blockRe      = regexp.MustCompile(`(?s)\{\{#(if|each)\s+([^}]+)\}\}(.*?)\{\{/(if|each)\}\}`)
+	helperCallRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][\w]*)((?:\s+(?:"[^"]*"|\S+))*)\s*\}\}`)
+)
+
+// resolveContent expands block sections and helper calls in content,
+// using env as the initial helper context. It runs after $env:
+// substitution so helpers can operate on already-resolved text.
+func resolveContent(content string, env map[string]string) (string, error) {
+	ctx := make(map[string]interface{}, len(env))
+	for k, v := range env {
+		ctx[k] = v
+	}
+
+	content, err := resolveBlocks(content, ctx)
+	if err != nil {
+		return "", err
+	}
+	return resolveInline(content, ctx)
+}
+
+// resolveBlocks expands {{#if cond}}...{{/if}} and {{#each items}}...{{/each}}
+// sections, re-evaluating each pass with resolveInline.
+func resolveBlocks(content string, ctx map[string]interface{}) (string, error) {
+	for {
+		loc := blockRe.FindStringSubmatchIndex(content)
+		if loc == nil {
+			break
+		}
+		kind := content[loc[2]:loc[3]]
+		arg := strings.TrimSpace(content[loc[4]:loc[5]])
+		body := content[loc[6]:loc[7]]
+
+		var replacement string
+		switch kind {
+		case "if":
+			if truthy(ctx[arg]) {
+				resolved, err := resolveInline(body, ctx)
+				if err != nil {
+					return "", err
+				}
+				replacement = resolved
+			}
+		case "each":
+			items := splitEachItems(ctx[arg])
+			var sb strings.Builder
+			for index, item := range items {
+				pass := strings.ReplaceAll(body, "{{.}}", item)
+				pass = strings.ReplaceAll(pass, "{{@index}}", strconv.Itoa(index))
+				resolved, err := resolveInline(pass, ctx)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(resolved)
+			}
+			replacement = sb.String()
+		}
+		content = content[:loc[0]] + replacement + content[loc[1]:]
+	}
+	return content, nil
+}
+
+// resolveInline expands {{name arg1 "arg2"}} calls against DefaultRegistry.
+// Calls to unregistered names are left untouched.
+func resolveInline(content string, ctx map[string]interface{}) (string, error) {
+	var callErr error
+	result := helperCallRe.ReplaceAllStringFunc(content, func(match string) string {
+		if callErr != nil {
+			return match
+		}
+		sub := helperCallRe.FindStringSubmatch(match)
+		name := sub[1]
+		fn, ok := DefaultRegistry.helpers[name]
+		if !ok {
+			return match
+		}
+		val, err := fn(splitArgs(sub[2]), ctx)
+		if err != nil {
+			callErr = err
+			return match
+		}
+		return val
+	})
+	if callErr != nil {
+		return "", callErr
+	}
+	return result, nil
+}
+
+// splitArgs tokenizes a helper call's argument string, honoring
+// double-quoted arguments containing spaces.
+func splitArgs(s string) []string {
+	var args []string
+	s = strings.TrimSpace(s)
+	for s != "" {
+		if s[0] == '"' {
+			end := strings.Index(s[1:], `"`)
+			if end == -1 {
+				args = append(args, s[1:])
+				break
+			}
+			args = append(args, s[1:1+end])
+			s = strings.TrimSpace(s[1+end+1:])
+			continue
+		}
+		idx := strings.IndexAny(s, " \t")
+		if idx == -1 {
+			args = append(args, s)
+			break
+		}
+		args = append(args, s[:idx])
+		s = strings.TrimSpace(s[idx:])
+	}
+	return args
+}
+
+// truthy reports whether v should be treated as true by {{#if}}.
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case string:
+		return val != "" && val != "false" && val != "0"
+	default:
+		return true
+	}
+}
+
+// splitEachItems coerces v into the item list iterated by {{#each}}.
+// Strings are split on commas so env-declared lists work without
+// requiring a richer data model.
+func splitEachItems(v interface{}) []string {
+	switch val := v.(type) {
+	case []string:
+		return val
+	case []interface{}:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+		return items
+	case string:
+		if val == "" {
+			return nil
+		}
+		parts := strings.Split(val, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		return parts
+	default:
+		return nil
+	}
+}
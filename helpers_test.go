@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MIT
+package jtl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OrtheSnowJames/jtl"
+)
+
+func TestParseWithHelpers(t *testing.T) {
+	jtl.RegisterHelper("upper", func(args []string, ctx map[string]interface{}) (string, error) {
+		if len(args) == 0 {
+			return "", nil
+		}
+		return strings.ToUpper(args[0]), nil
+	})
+	defer jtl.RemoveHelper("upper")
+
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" tag="span">greeting>{{upper "hello"}};
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	elem := parsed[0].(map[string]interface{})
+	if elem["Content"] != "HELLO" {
+		t.Errorf("Content = %q, want %q", elem["Content"], "HELLO")
+	}
+}
+
+func TestParseWithIfBlock(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>ENV;
+    >>>ADMIN=true;
+>>>BEGIN;
+    >class="main" tag="span">panel>{{#if ADMIN}}admin panel{{/if}};
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	elem := parsed[0].(map[string]interface{})
+	if elem["Content"] != "admin panel" {
+		t.Errorf("Content = %q, want %q", elem["Content"], "admin panel")
+	}
+}
+
+func TestParseWithEachBlock(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>ENV;
+    >>>NAMES=alice,bob;
+>>>BEGIN;
+    >class="main" tag="ul">list>{{#each NAMES}}[{{@index}}:{{.}}]{{/each}};
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	elem := parsed[0].(map[string]interface{})
+	want := "[0:alice][1:bob]"
+	if elem["Content"] != want {
+		t.Errorf("Content = %q, want %q", elem["Content"], want)
+	}
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: MIT
+
+// Package lexer tokenizes JTL source text into a flat, position-tracked
+// token stream for the grammar-driven parser in jtl/parser.
+package lexer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TokenType identifies the lexical class of a Token.
+type TokenType int
+
+// Token kinds produced by the Lexer.
+const (
+	TOKEN_EOF TokenType = iota
+	TOKEN_ILLEGAL
+	TOKEN_DOCTYPE
+	TOKEN_ENV_SECTION
+	TOKEN_ENV_DECL
+	TOKEN_BEGIN
+	TOKEN_END
+	TOKEN_ELEM_OPEN
+	TOKEN_ATTR
+	TOKEN_ELEM_ID
+	TOKEN_CONTENT
+	TOKEN_SEMI
+	TOKEN_BRACKET_OPEN
+	TOKEN_BRACKET_CLOSE
+)
+
+var tokenNames = map[TokenType]string{
+	TOKEN_EOF:           "EOF",
+	TOKEN_ILLEGAL:       "ILLEGAL",
+	TOKEN_DOCTYPE:       "DOCTYPE",
+	TOKEN_ENV_SECTION:   "ENV_SECTION",
+	TOKEN_ENV_DECL:      "ENV_DECL",
+	TOKEN_BEGIN:         "BEGIN",
+	TOKEN_END:           "END",
+	TOKEN_ELEM_OPEN:     "ELEM_OPEN",
+	TOKEN_ATTR:          "ATTR",
+	TOKEN_ELEM_ID:       "ELEM_ID",
+	TOKEN_CONTENT:       "CONTENT",
+	TOKEN_SEMI:          "SEMI",
+	TOKEN_BRACKET_OPEN:  "BRACKET_OPEN",
+	TOKEN_BRACKET_CLOSE: "BRACKET_CLOSE",
+}
+
+// String returns the token kind's name, used in error messages.
+func (t TokenType) String() string {
+	if name, ok := tokenNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// Pos identifies a location in the source text.
+type Pos struct {
+	Line   int
+	Col    int
+	Offset int
+}
+
+// Token is a single lexical unit produced by the Lexer.
+type Token struct {
+	Type    TokenType
+	Literal string
+	Pos     Pos
+}
+
+var attrRe = regexp.MustCompile(`\w+="[^"]*"`)
+
+// Lexer scans JTL source text line by line into a flat token stream.
+// Unlike the map-based parsing in the top-level jtl package, it never
+// stops at the first malformed line: it emits TOKEN_ILLEGAL and keeps
+// going, so the parser can collect every error in a document.
+type Lexer struct {
+	lines []string
+	line  int
+	queue []Token
+}
+
+// New creates a Lexer over src.
+func New(src []byte) *Lexer {
+	return &Lexer{lines: strings.Split(string(src), "\n")}
+}
+
+// Next returns the next token, or a TOKEN_EOF token once src is
+// exhausted.
+func (l *Lexer) Next() Token {
+	for len(l.queue) == 0 {
+		if l.line >= len(l.lines) {
+			return Token{Type: TOKEN_EOF, Pos: l.pos(len(l.lines), 0)}
+		}
+		l.scanLine(l.lines[l.line])
+		l.line++
+	}
+	tok := l.queue[0]
+	l.queue = l.queue[1:]
+	return tok
+}
+
+// All lexes the remainder of src into a slice of tokens terminated by a
+// single TOKEN_EOF.
+func (l *Lexer) All() []Token {
+	var toks []Token
+	for {
+		tok := l.Next()
+		toks = append(toks, tok)
+		if tok.Type == TOKEN_EOF {
+			return toks
+		}
+	}
+}
+
+func (l *Lexer) pos(lineIdx, col int) Pos {
+	offset := 0
+	for i := 0; i < lineIdx && i < len(l.lines); i++ {
+		offset += len(l.lines[i]) + 1
+	}
+	return Pos{Line: lineIdx + 1, Col: col + 1, Offset: offset + col}
+}
+
+func (l *Lexer) scanLine(raw string) {
+	lineIdx := l.line
+	indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+	trimmed := strings.TrimSpace(raw)
+
+	if trimmed == "" || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*/") || strings.HasPrefix(trimmed, ">//>") {
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(trimmed, ">>>DOCTYPE="):
+		value := strings.TrimSuffix(strings.TrimPrefix(trimmed, ">>>DOCTYPE="), ";")
+		l.emit(TOKEN_DOCTYPE, value, lineIdx, indent)
+	case trimmed == ">>>ENV;":
+		l.emit(TOKEN_ENV_SECTION, "", lineIdx, indent)
+	case trimmed == ">>>BEGIN;":
+		l.emit(TOKEN_BEGIN, "", lineIdx, indent)
+	case trimmed == ">>>END;":
+		l.emit(TOKEN_END, "", lineIdx, indent)
+	case strings.HasPrefix(trimmed, ">>>"):
+		for _, decl := range strings.Split(trimmed, ";") {
+			decl = strings.TrimSpace(decl)
+			if strings.HasPrefix(decl, ">>>") {
+				l.emit(TOKEN_ENV_DECL, strings.TrimPrefix(decl, ">>>"), lineIdx, indent)
+			}
+		}
+	case strings.HasPrefix(trimmed, ">"):
+		l.scanElement(trimmed, lineIdx, indent)
+	default:
+		l.emit(TOKEN_ILLEGAL, trimmed, lineIdx, indent)
+	}
+}
+
+// scanElement lexes a body element that may span multiple source lines
+// (e.g. embedded lua/script blocks): it keeps pulling lines from l.lines,
+// the same way stream.go's step() accumulates fullContent, until one
+// ends with ";" or the source runs out. Without this, a continuation
+// line would be left untokenized and silently dropped instead of
+// surfacing as a parse error.
+func (l *Lexer) scanElement(firstLine string, lineIdx, indent int) {
+	l.emit(TOKEN_ELEM_OPEN, "", lineIdx, indent)
+
+	fullContent := firstLine
+	terminated := strings.HasSuffix(fullContent, ";")
+	for !terminated && l.line+1 < len(l.lines) {
+		l.line++
+		fullContent += "\n" + l.lines[l.line]
+		terminated = strings.HasSuffix(strings.TrimSpace(l.lines[l.line]), ";")
+	}
+
+	rest := strings.TrimPrefix(fullContent, ">")
+	if terminated {
+		rest = strings.TrimSuffix(strings.TrimSpace(rest), ";")
+	}
+
+	firstSep := strings.Index(rest, ">")
+	if firstSep == -1 {
+		l.emit(TOKEN_ILLEGAL, rest, lineIdx, indent)
+		return
+	}
+	attrsPart := rest[:firstSep]
+	remainder := rest[firstSep+1:]
+
+	for _, match := range attrRe.FindAllString(attrsPart, -1) {
+		l.emit(TOKEN_ATTR, match, lineIdx, indent)
+	}
+
+	secondSep := strings.Index(remainder, ">")
+	if secondSep == -1 {
+		l.emit(TOKEN_ILLEGAL, remainder, lineIdx, indent)
+		return
+	}
+	id := strings.TrimSpace(remainder[:secondSep])
+	content := remainder[secondSep+1:]
+
+	l.emit(TOKEN_ELEM_ID, id, lineIdx, indent)
+
+	hasOpen := strings.Contains(content, "[[")
+	hasClose := strings.Contains(content, "]]")
+	if hasOpen {
+		l.emit(TOKEN_BRACKET_OPEN, "[[", lineIdx, indent)
+	}
+	l.emit(TOKEN_CONTENT, strings.TrimSpace(content), lineIdx, indent)
+	if hasClose {
+		l.emit(TOKEN_BRACKET_CLOSE, "]]", lineIdx, indent)
+	}
+	if terminated {
+		l.emit(TOKEN_SEMI, ";", lineIdx, indent)
+	} else {
+		l.emit(TOKEN_ILLEGAL, fullContent, lineIdx, indent)
+	}
+}
+
+func (l *Lexer) emit(t TokenType, literal string, lineIdx, col int) {
+	l.queue = append(l.queue, Token{Type: t, Literal: literal, Pos: l.pos(lineIdx, col)})
+}
@@ -4,107 +4,31 @@ package jtl
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"regexp"
 	"strings"
 )
 
+// elementAttrRe matches a single key="value" attribute pair. It is
+// compiled once at package init rather than per parseElement call.
+var elementAttrRe = regexp.MustCompile(`(\w+)="([^"]+)"`)
+
 // Parse parses JTL content into a structured slice of interfaces.
 func Parse(text string) ([]interface{}, error) {
 	var result []interface{}
-	lines := strings.Split(text, "\n")
+	s := newScanState(strings.NewReader(text))
+	defer s.release()
 
-	if len(lines) == 0 || !strings.Contains(lines[0], "DOCTYPE=JTL") {
-		return nil, errors.New("invalid JTL document: missing DOCTYPE")
-	}
-
-	inBody := false
-	inEnv := false
-	currentEnv := make(map[string]string)
-
-	type stackItem struct {
-		element interface{}
-		indent  int
-	}
-	stack := []stackItem{}
-
-	for i := 0; i < len(lines); i++ {
-		indent := countIndentation(lines[i])
-		line := strings.TrimSpace(lines[i])
-
-		if line == "" || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*/") || strings.HasPrefix(line, ">//>") {
-			continue
+	for {
+		element, isRoot, err := s.step()
+		if err == io.EOF {
+			break
 		}
-
-		// Handle section markers
-		switch line {
-		case ">>>ENV;":
-			inEnv = true
-			continue
-		case ">>>BEGIN;":
-			inEnv = false
-			inBody = true
-			continue
-		case ">>>END;":
-			inBody = false
-			continue
+		if err != nil {
+			return nil, err
 		}
-
-		// Handle environment variables
-		if inEnv && strings.HasPrefix(line, ">>>") {
-			declarations := strings.Split(line, ";")
-			for _, declaration := range declarations {
-				declaration = strings.TrimSpace(declaration)
-				if strings.HasPrefix(declaration, ">>>") {
-					parts := strings.SplitN(declaration[3:], "=", 2)
-					if len(parts) == 2 {
-						varName := strings.TrimSpace(parts[0])
-						varValue := strings.TrimSpace(parts[1])
-						currentEnv[varName] = varValue
-					}
-				}
-			}
-			continue
-		}
-
-		// Handle body elements
-		if inBody && strings.HasPrefix(line, ">") {
-			// Collect multi-line content
-			fullContent := line
-			if !strings.HasSuffix(line, ";") {
-				for j := i + 1; j < len(lines); j++ {
-					nextLine := lines[j]
-					fullContent += "\n" + nextLine
-					if strings.HasSuffix(strings.TrimSpace(nextLine), ";") {
-						i = j // Skip processed lines
-						break
-					}
-				}
-			}
-
-			element, err := parseElement(fullContent, currentEnv)
-			if err != nil {
-				return nil, err
-			}
-
-			// Pop stack items with greater or equal indentation
-			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
-				stack = stack[:len(stack)-1]
-			}
-
-			if len(stack) > 0 {
-				// Add as child to parent
-				parent := stack[len(stack)-1].element.(map[string]interface{})
-				if _, exists := parent["children"]; !exists {
-					parent["children"] = make([]interface{}, 0)
-				}
-				parent["children"] = append(parent["children"].([]interface{}), element)
-			} else {
-				// Root level element
-				result = append(result, element)
-			}
-
-			// Push current element to stack
-			stack = append(stack, stackItem{element, indent})
+		if isRoot {
+			result = append(result, element)
 		}
 	}
 
@@ -182,9 +106,18 @@ func parseElement(elementText string, env map[string]string) (interface{}, error
 		}
 	}
 
+	// Expand {{#if}}/{{#each}} block sections and calls to helpers
+	// registered via RegisterHelper.
+	if content != "" {
+		resolved, err := resolveContent(content, env)
+		if err != nil {
+			return nil, err
+		}
+		content = resolved
+	}
+
 	// Process attributes using a regex.
-	attrRegex := regexp.MustCompile(`(\w+)="([^"]+)"`)
-	matches := attrRegex.FindAllStringSubmatch(attributesPart, -1)
+	matches := elementAttrRe.FindAllStringSubmatch(attributesPart, -1)
 	if len(matches) == 0 {
 		return nil, errors.New("invalid element format: no attributes found")
 	}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+package jtl_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/OrtheSnowJames/jtl"
+)
+
+const smallBenchDoc = `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" tag="span">greeting>Hello, World!;
+>>>END;`
+
+const bracketedBenchDoc = `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >type="lua">script>[[
+        document.onEvent(".buttontest", "click", [[
+            print("Button clicked!")
+        ]];
+>>>END;`
+
+func complexBenchDoc() string {
+	var b strings.Builder
+	b.WriteString(">>>DOCTYPE=JTL;\n>>>BEGIN;\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&b, "    >class=\"item\" tag=\"li\">item%d>Item number %d;\n", i, i)
+	}
+	b.WriteString(">>>END;")
+	return b.String()
+}
+
+func envHeavyBenchDoc() string {
+	var b strings.Builder
+	b.WriteString(">>>DOCTYPE=JTL;\n>>>ENV;\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "    >>>VAR%d=value%d;\n", i, i)
+	}
+	b.WriteString(">>>BEGIN;\n    >class=\"main\" tag=\"span\">greeting>$env:VAR0;\n>>>END;")
+	return b.String()
+}
+
+func BenchmarkParseSmall(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := jtl.Parse(smallBenchDoc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseComplex(b *testing.B) {
+	doc := complexBenchDoc()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jtl.Parse(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseBracketed(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := jtl.Parse(bracketedBenchDoc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseEnvHeavy(b *testing.B) {
+	doc := envHeavyBenchDoc()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jtl.Parse(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStringify(b *testing.B) {
+	parsed, err := jtl.Parse(complexBenchDoc())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := jtl.Stringify(parsed); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: MIT
+
+// Package parser builds a position-tracked AST from the token stream
+// produced by jtl/lexer, modeled after the lexer+parser split used by
+// go/parser. Unlike jtl.Parse, it accumulates every malformed construct
+// into an ErrorList instead of stopping at the first one, so tooling
+// built on top of it can report all issues in a document at once.
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OrtheSnowJames/jtl/lexer"
+)
+
+// Node is implemented by every AST node produced by Parse.
+type Node interface {
+	Pos() lexer.Pos
+}
+
+// Document is the root AST node for a parsed JTL source file.
+type Document struct {
+	Version  string
+	Env      *EnvBlock
+	Elements []*Element
+	pos      lexer.Pos
+}
+
+// Pos returns the position of the document's DOCTYPE declaration.
+func (d *Document) Pos() lexer.Pos { return d.pos }
+
+// EnvBlock holds the >>>NAME=value; declarations between >>>ENV; and
+// >>>BEGIN;.
+type EnvBlock struct {
+	Vars map[string]string
+	pos  lexer.Pos
+}
+
+// Pos returns the position of the >>>ENV; marker.
+func (e *EnvBlock) Pos() lexer.Pos { return e.pos }
+
+// Element is a single >attrs>id>content; body element. Children are
+// attached according to the indentation of their ELEM_OPEN token,
+// matching the stack-based nesting rule used by jtl.Parse.
+type Element struct {
+	Attrs    map[string]string
+	ID       string
+	Content  string
+	Children []*Element
+	pos      lexer.Pos
+}
+
+// Pos returns the position of the element's opening ">".
+func (e *Element) Pos() lexer.Pos { return e.pos }
+
+// ParseError records one malformed construct along with the position it
+// occurred at.
+type ParseError struct {
+	Pos lexer.Pos
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Col, e.Msg)
+}
+
+// ErrorList collects every ParseError found while parsing a document.
+type ErrorList []ParseError
+
+func (errs ErrorList) Error() string {
+	switch len(errs) {
+	case 0:
+		return "no errors"
+	case 1:
+		return errs[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", errs[0].Error(), len(errs)-1)
+	}
+}
+
+type elemFrame struct {
+	elem   *Element
+	indent int
+}
+
+type parser struct {
+	toks   []lexer.Token
+	pos    int
+	errors ErrorList
+}
+
+// Parse lexes and parses src into a Document. Malformed constructs are
+// collected into the returned ErrorList rather than stopping the parse
+// at the first one; the returned Document holds whatever was
+// successfully recovered around them.
+func Parse(src []byte) (*Document, error) {
+	p := &parser{toks: lexer.New(src).All()}
+	doc := p.parseDocument()
+	if len(p.errors) > 0 {
+		return doc, p.errors
+	}
+	return doc, nil
+}
+
+func (p *parser) cur() lexer.Token {
+	if p.pos >= len(p.toks) {
+		return p.toks[len(p.toks)-1]
+	}
+	return p.toks[p.pos]
+}
+
+func (p *parser) advance() lexer.Token {
+	tok := p.cur()
+	if p.pos < len(p.toks)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok lexer.Token, format string, args ...interface{}) {
+	p.errors = append(p.errors, ParseError{Pos: tok.Pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) parseDocument() *Document {
+	doc := &Document{pos: p.cur().Pos}
+
+	tok := p.advance()
+	if tok.Type != lexer.TOKEN_DOCTYPE {
+		p.errorf(tok, "expected DOCTYPE, got %s", tok.Type)
+	} else {
+		doc.Version = tok.Literal
+		doc.pos = tok.Pos
+	}
+
+	if p.cur().Type == lexer.TOKEN_ENV_SECTION {
+		doc.Env = p.parseEnvBlock()
+	}
+
+	for p.cur().Type != lexer.TOKEN_BEGIN && p.cur().Type != lexer.TOKEN_EOF {
+		p.errorf(p.cur(), "unexpected %s before BEGIN", p.cur().Type)
+		p.advance()
+	}
+	if p.cur().Type == lexer.TOKEN_BEGIN {
+		p.advance()
+	}
+
+	doc.Elements = p.parseElements()
+
+	if p.cur().Type == lexer.TOKEN_END {
+		p.advance()
+	}
+
+	return doc
+}
+
+func (p *parser) parseEnvBlock() *EnvBlock {
+	env := &EnvBlock{Vars: make(map[string]string), pos: p.cur().Pos}
+	p.advance() // consume TOKEN_ENV_SECTION
+
+	for p.cur().Type == lexer.TOKEN_ENV_DECL {
+		tok := p.advance()
+		parts := strings.SplitN(tok.Literal, "=", 2)
+		if len(parts) != 2 {
+			p.errorf(tok, "malformed env declaration %q", tok.Literal)
+			continue
+		}
+		env.Vars[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return env
+}
+
+func (p *parser) parseElements() []*Element {
+	var roots []*Element
+	var stack []elemFrame
+
+	for p.cur().Type != lexer.TOKEN_END && p.cur().Type != lexer.TOKEN_EOF {
+		if p.cur().Type != lexer.TOKEN_ELEM_OPEN {
+			p.errorf(p.cur(), "unexpected %s in body", p.cur().Type)
+			p.advance()
+			continue
+		}
+		elem := p.parseElement()
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= elem.pos.Col {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1].elem
+			parent.Children = append(parent.Children, elem)
+		} else {
+			roots = append(roots, elem)
+		}
+
+		stack = append(stack, elemFrame{elem, elem.pos.Col})
+	}
+
+	return roots
+}
+
+func (p *parser) parseElement() *Element {
+	open := p.advance() // TOKEN_ELEM_OPEN
+	elem := &Element{Attrs: make(map[string]string), pos: open.Pos}
+
+	for p.cur().Type == lexer.TOKEN_ATTR {
+		tok := p.advance()
+		parts := strings.SplitN(tok.Literal, "=", 2)
+		if len(parts) != 2 {
+			p.errorf(tok, "malformed attribute %q", tok.Literal)
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		value := strings.Trim(strings.TrimSpace(parts[1]), `"`)
+		elem.Attrs[name] = value
+	}
+
+	if p.cur().Type == lexer.TOKEN_ELEM_ID {
+		elem.ID = p.advance().Literal
+	}
+
+	if p.cur().Type == lexer.TOKEN_BRACKET_OPEN {
+		p.advance()
+	}
+
+	if p.cur().Type == lexer.TOKEN_CONTENT {
+		elem.Content = p.advance().Literal
+	}
+
+	if p.cur().Type == lexer.TOKEN_BRACKET_CLOSE {
+		p.advance()
+	}
+
+	if p.cur().Type == lexer.TOKEN_SEMI {
+		p.advance()
+	} else {
+		p.errorf(p.cur(), "expected ';' to terminate element, got %s", p.cur().Type)
+		if p.cur().Type == lexer.TOKEN_ILLEGAL {
+			p.advance() // the malformed remainder of this element
+		}
+	}
+
+	return elem
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OrtheSnowJames/jtl/parser"
+)
+
+func TestParseDocument(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>ENV;
+    >>>NAME=developer;
+>>>BEGIN;
+    >class="main" tag="test">test>$env:NAME;
+>>>END;`
+
+	doc, err := parser.Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if doc.Version != "JTL" {
+		t.Errorf("Version = %q, want %q", doc.Version, "JTL")
+	}
+	if doc.Env == nil || doc.Env.Vars["NAME"] != "developer" {
+		t.Errorf("Env.Vars[NAME] = %v, want %q", doc.Env, "developer")
+	}
+	if len(doc.Elements) != 1 {
+		t.Fatalf("len(Elements) = %d, want 1", len(doc.Elements))
+	}
+	if doc.Elements[0].ID != "test" {
+		t.Errorf("Elements[0].ID = %q, want %q", doc.Elements[0].ID, "test")
+	}
+}
+
+func TestParseMultiLineElement(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >type="lua">script>
+        document.onEvent(".buttontest", "click", [[
+            print("Button clicked!")
+            -- Do more stuff here
+        ]]);
+    >class="buttontest">button>Test Button;
+>>>END;`
+
+	doc, err := parser.Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(doc.Elements) != 2 {
+		t.Fatalf("len(Elements) = %d, want 2 (multi-line script element must not swallow the button element)", len(doc.Elements))
+	}
+	if doc.Elements[0].ID != "script" {
+		t.Errorf("Elements[0].ID = %q, want %q", doc.Elements[0].ID, "script")
+	}
+	if doc.Elements[0].Content == "" {
+		t.Error("Elements[0].Content is empty, want the script body")
+	}
+	if !strings.Contains(doc.Elements[0].Content, "onEvent") {
+		t.Errorf("Elements[0].Content = %q, want it to contain the script body", doc.Elements[0].Content)
+	}
+	if doc.Elements[1].ID != "button" {
+		t.Errorf("Elements[1].ID = %q, want %q", doc.Elements[1].ID, "button")
+	}
+}
+
+func TestParseElementWithRealIDAttribute(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" id="realattr">myid>content;
+>>>END;`
+
+	doc, err := parser.Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(doc.Elements) != 1 {
+		t.Fatalf("len(Elements) = %d, want 1", len(doc.Elements))
+	}
+	elem := doc.Elements[0]
+	if elem.ID != "myid" {
+		t.Errorf("ID = %q, want %q", elem.ID, "myid")
+	}
+	if elem.Attrs["id"] != "realattr" {
+		t.Errorf(`Attrs["id"] = %q, want %q (a real "id" attribute must not be shadowed by the element's own id)`, elem.Attrs["id"], "realattr")
+	}
+}
+
+func TestParseCollectsAllErrors(t *testing.T) {
+	// Each malformed line is terminated with ";" so it is not mistaken
+	// for a continuation of the line before it (see
+	// TestParseMultiLineElement for the continuation case).
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >invalid>test;
+    >also="bad">id2;
+>>>END;`
+
+	_, err := parser.Parse([]byte(input))
+	if err == nil {
+		t.Fatal("Parse() error = nil, want accumulated errors")
+	}
+
+	errs, ok := err.(parser.ErrorList)
+	if !ok {
+		t.Fatalf("error type = %T, want parser.ErrorList", err)
+	}
+	if len(errs) != 2 {
+		t.Errorf("len(ErrorList) = %d, want 2 (one per malformed element): %v", len(errs), errs)
+	}
+}
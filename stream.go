@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: MIT
+package jtl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Element represents a single parsed JTL element, matching the map shape
+// produced by Parse.
+type Element = map[string]interface{}
+
+type section int
+
+const (
+	sectionHeader section = iota
+	sectionEnv
+	sectionBody
+)
+
+type stackFrame struct {
+	element Element
+	indent  int
+}
+
+// stackPool recycles the []stackFrame backing array across Parse calls,
+// since Parse fully owns a scanState's lifetime and knows when it is
+// safe to return the slice.
+var stackPool = sync.Pool{
+	New: func() interface{} {
+		s := make([]stackFrame, 0, 16)
+		return &s
+	},
+}
+
+// scanState holds the state machine shared by Parse and Parser: it reads
+// lines from a bufio.Scanner and produces one element at a time instead
+// of requiring the whole document to be sliced into a []string up front.
+type scanState struct {
+	scanner  *bufio.Scanner
+	section  section
+	env      map[string]string
+	stack    []stackFrame
+	stackPtr *[]stackFrame
+	started  bool
+}
+
+// maxScanTokenSize bounds how long a single source line (e.g. an
+// embedded lua/script block packed onto one line) can be, for both Parse
+// and Parser. It is well beyond any realistic JTL document and replaces
+// bufio.Scanner's 64 KiB default, which one long line could exceed.
+const maxScanTokenSize = 64 * 1024 * 1024
+
+func newScanState(r io.Reader) *scanState {
+	stackPtr := stackPool.Get().(*[]stackFrame)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScanTokenSize)
+	return &scanState{
+		scanner:  scanner,
+		env:      make(map[string]string),
+		stack:    (*stackPtr)[:0],
+		stackPtr: stackPtr,
+	}
+}
+
+// release returns s's stack slice to stackPool for reuse by a later
+// Parse call. Parser does not call this, since it owns the scanState
+// for an open-ended lifetime.
+func (s *scanState) release() {
+	if s.stackPtr == nil {
+		return
+	}
+	*s.stackPtr = s.stack[:0]
+	stackPool.Put(s.stackPtr)
+	s.stackPtr = nil
+}
+
+// step scans forward until it completes one element, returning the
+// element and whether it sits at the document root, or returns io.EOF
+// once the input is exhausted.
+func (s *scanState) step() (Element, bool, error) {
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+
+		if !s.started {
+			s.started = true
+			if !strings.Contains(line, "DOCTYPE=JTL") {
+				return nil, false, errors.New("invalid JTL document: missing DOCTYPE")
+			}
+		}
+
+		indent := countIndentation(line)
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*/") || strings.HasPrefix(trimmed, ">//>") {
+			continue
+		}
+
+		switch trimmed {
+		case ">>>ENV;":
+			s.section = sectionEnv
+			continue
+		case ">>>BEGIN;":
+			s.section = sectionBody
+			continue
+		case ">>>END;":
+			s.section = sectionHeader
+			continue
+		}
+
+		if s.section == sectionEnv && strings.HasPrefix(trimmed, ">>>") {
+			parseEnvLine(trimmed, s.env)
+			continue
+		}
+
+		if s.section == sectionBody && strings.HasPrefix(trimmed, ">") {
+			fullContent := trimmed
+			for !strings.HasSuffix(strings.TrimSpace(fullContent), ";") && s.scanner.Scan() {
+				fullContent += "\n" + s.scanner.Text()
+			}
+			if err := s.scanner.Err(); err != nil {
+				return nil, false, err
+			}
+
+			parsed, err := parseElement(fullContent, s.env)
+			if err != nil {
+				return nil, false, err
+			}
+			elem := parsed.(Element)
+
+			for len(s.stack) > 0 && s.stack[len(s.stack)-1].indent >= indent {
+				s.stack = s.stack[:len(s.stack)-1]
+			}
+
+			isRoot := len(s.stack) == 0
+			if !isRoot {
+				parent := s.stack[len(s.stack)-1].element
+				if _, exists := parent["children"]; !exists {
+					parent["children"] = make([]interface{}, 0)
+				}
+				parent["children"] = append(parent["children"].([]interface{}), elem)
+			}
+
+			s.stack = append(s.stack, stackFrame{elem, indent})
+			return elem, isRoot, nil
+		}
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, false, err
+	}
+	if !s.started {
+		return nil, false, errors.New("invalid JTL document: missing DOCTYPE")
+	}
+	return nil, false, io.EOF
+}
+
+// parseEnvLine parses one or more semicolon-separated ">>>NAME=value"
+// declarations from a single ENV-section line into env.
+func parseEnvLine(line string, env map[string]string) {
+	declarations := strings.Split(line, ";")
+	for _, declaration := range declarations {
+		declaration = strings.TrimSpace(declaration)
+		if strings.HasPrefix(declaration, ">>>") {
+			parts := strings.SplitN(declaration[3:], "=", 2)
+			if len(parts) == 2 {
+				varName := strings.TrimSpace(parts[0])
+				varValue := strings.TrimSpace(parts[1])
+				env[varName] = varValue
+			}
+		}
+	}
+}
+
+// Parser scans a JTL document from an io.Reader and emits one Element at
+// a time via Next, so large documents don't need to be loaded into
+// memory as a whole, unlike Parse, which takes the document as a string.
+type Parser struct {
+	state *scanState
+}
+
+// NewParser creates a Parser that reads JTL from r.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{state: newScanState(r)}
+}
+
+// Next returns the next element in document order, or io.EOF once the
+// document is exhausted. Elements that contain nested children are
+// returned as soon as they are opened; their "children" key is
+// populated as nested elements are scanned on later calls.
+func (p *Parser) Next() (Element, error) {
+	elem, _, err := p.state.step()
+	if err != nil {
+		return nil, err
+	}
+	return elem, nil
+}
+
+// Encoder writes JTL elements to an underlying writer incrementally,
+// mirroring Parser's read-side streaming.
+type Encoder struct {
+	w       io.Writer
+	started bool
+}
+
+// NewEncoder creates an Encoder that writes JTL to w, emitting the
+// DOCTYPE/BEGIN header before the first element.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes elem as a JTL body line, indenting it to match its
+// nesting depth, and recursively encodes any children in elem's
+// "children" key (as nested by Parse) so a tree round-trips instead of
+// flattening into siblings.
+func (e *Encoder) Encode(elem Element) error {
+	return e.encode(elem, 0)
+}
+
+func (e *Encoder) encode(elem Element, depth int) error {
+	if !e.started {
+		if _, err := io.WriteString(e.w, ">>>DOCTYPE=JTL;\n>>>BEGIN;\n"); err != nil {
+			return err
+		}
+		e.started = true
+	}
+
+	var attrs strings.Builder
+	id := ""
+	content := ""
+	var children []interface{}
+	for key, value := range elem {
+		switch key {
+		case "KEY":
+			id, _ = value.(string)
+		case "Content":
+			content, _ = value.(string)
+		case "Contents":
+			// Contents duplicates Content.
+		case "children":
+			children, _ = value.([]interface{})
+		default:
+			fmt.Fprintf(&attrs, "%s=%q ", key, fmt.Sprint(value))
+		}
+	}
+
+	indent := strings.Repeat("    ", depth+1)
+	if _, err := fmt.Fprintf(e.w, "%s>%s>%s>%s;\n", indent, strings.TrimSpace(attrs.String()), id, content); err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		childElem, ok := child.(Element)
+		if !ok {
+			return fmt.Errorf("jtl: unexpected child node type %T", child)
+		}
+		if err := e.encode(childElem, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close writes the closing ">>>END;" marker.
+func (e *Encoder) Close() error {
+	_, err := io.WriteString(e.w, ">>>END;\n")
+	return err
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+package jtl_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/OrtheSnowJames/jtl"
+)
+
+func TestParserNext(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" tag="test">test>Hello;
+    >class="main" tag="test">test2>World;
+>>>END;`
+
+	p := jtl.NewParser(strings.NewReader(input))
+
+	var keys []string
+	for {
+		elem, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		keys = append(keys, elem["KEY"].(string))
+	}
+
+	want := []string{"test", "test2"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+func TestParserMatchesParse(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="main" tag="test">test>content;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	p := jtl.NewParser(strings.NewReader(input))
+	elem, err := p.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	if elem["KEY"] != parsed[0].(map[string]interface{})["KEY"] {
+		t.Errorf("Next() KEY = %v, want %v", elem["KEY"], parsed[0].(map[string]interface{})["KEY"])
+	}
+
+	if _, err := p.Next(); err != io.EOF {
+		t.Errorf("Next() error = %v, want io.EOF", err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := jtl.NewEncoder(&buf)
+
+	err := enc.Encode(jtl.Element{
+		"KEY":     "greeting",
+		"class":   "main",
+		"Content": "hello",
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{">>>DOCTYPE=JTL;", ">>>BEGIN;", "greeting", "hello", ">>>END;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Encode() output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseHandlesLongElementLines(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	input := ">>>DOCTYPE=JTL;\n>>>BEGIN;\n    >type=\"lua\">script>" + long + ";\n>>>END;"
+
+	doc, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(doc) != 1 {
+		t.Fatalf("len(doc) = %d, want 1", len(doc))
+	}
+	elem, _ := doc[0].(map[string]interface{})
+	if content, _ := elem["Content"].(string); content != long {
+		t.Errorf("Content length = %d, want %d", len(content), len(long))
+	}
+
+	p := jtl.NewParser(strings.NewReader(input))
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+}
+
+func TestEncoderPreservesNesting(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="parent">parent>Parent;
+        >class="child">child>Child;
+>>>END;`
+
+	parsed, err := jtl.Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc := jtl.NewEncoder(&buf)
+	for _, elem := range parsed {
+		if err := enc.Encode(elem.(jtl.Element)); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	roundTripped, err := jtl.Parse(buf.String())
+	if err != nil {
+		t.Fatalf("Parse() round-trip error = %v\n%s", err, buf.String())
+	}
+
+	if len(roundTripped) != 1 {
+		t.Fatalf("len(roundTripped) = %d, want 1 root element", len(roundTripped))
+	}
+	children, _ := roundTripped[0].(map[string]interface{})["children"].([]interface{})
+	if len(children) != 1 {
+		t.Fatalf("len(children) = %d, want 1 nested child, got flattened siblings instead:\n%s", len(children), buf.String())
+	}
+	if child, ok := children[0].(map[string]interface{}); !ok || child["KEY"] != "child" {
+		t.Errorf("children[0] = %v, want element with KEY=child", children[0])
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+package jtl
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Template renders a parsed JTL document back out to text, interpolating
+// $data: references against a Go value via reflection. This lets
+// callers ship one .jtl file plus a struct instead of pre-assembling
+// the maps Parse produces.
+type Template struct {
+	name string
+	docs []interface{}
+}
+
+// New creates an empty, unparsed Template named name.
+func New(name string) *Template {
+	return &Template{name: name}
+}
+
+// Name returns the template's name, as given to New.
+func (t *Template) Name() string { return t.name }
+
+// Parse parses text as a JTL document and attaches it to t, returning t
+// so calls can be chained as New(name).Parse(text).
+func (t *Template) Parse(text string) (*Template, error) {
+	docs, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	t.docs = docs
+	return t, nil
+}
+
+// Execute resolves $data: references in t's parsed tree against data via
+// reflection, then writes the result back out to w via EmitJTL so the
+// rendered output reproduces the document's tags, attributes, and
+// nesting instead of just concatenating every element's Content.
+func (t *Template) Execute(w io.Writer, data interface{}) error {
+	resolved := make([]interface{}, len(t.docs))
+	for i, node := range t.docs {
+		elem, err := resolveElement(node, data)
+		if err != nil {
+			return err
+		}
+		resolved[i] = elem
+	}
+	return EmitJTL(w, resolved)
+}
+
+// resolveElement copies node, substituting its Content with the looked
+// up $data: value (if any), and does the same recursively for its
+// children, leaving node itself untouched.
+func resolveElement(node interface{}, data interface{}) (Element, error) {
+	elem, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("jtl: unexpected node type %T", node)
+	}
+
+	resolved := make(Element, len(elem))
+	for key, value := range elem {
+		resolved[key] = value
+	}
+
+	content, _ := elem["Content"].(string)
+	if strings.HasPrefix(content, "$data:") {
+		path := strings.TrimPrefix(content, "$data:")
+		val, err := lookupPath(data, path)
+		if err != nil {
+			return nil, err
+		}
+		resolved["Content"] = fmt.Sprint(val)
+		resolved["Contents"] = resolved["Content"]
+	}
+
+	if children, ok := elem["children"].([]interface{}); ok {
+		resolvedChildren := make([]interface{}, len(children))
+		for i, child := range children {
+			resolvedChild, err := resolveElement(child, data)
+			if err != nil {
+				return nil, err
+			}
+			resolvedChildren[i] = resolvedChild
+		}
+		resolved["children"] = resolvedChildren
+	}
+
+	return resolved, nil
+}
+
+// lookupPath resolves a dotted path such as "user.Name" or
+// "items.0.Title" against data, descending through maps by key, slices
+// and arrays by numeric index, and structs by field name or `jtl:"..."`
+// tag.
+func lookupPath(data interface{}, path string) (interface{}, error) {
+	current := reflect.ValueOf(data)
+
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+		for current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface {
+			if current.IsNil() {
+				return nil, fmt.Errorf("jtl: nil value at %q", segment)
+			}
+			current = current.Elem()
+		}
+
+		switch current.Kind() {
+		case reflect.Map:
+			val := current.MapIndex(reflect.ValueOf(segment))
+			if !val.IsValid() {
+				return nil, fmt.Errorf("jtl: no such key %q", segment)
+			}
+			current = val
+		case reflect.Slice, reflect.Array:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= current.Len() {
+				return nil, fmt.Errorf("jtl: invalid index %q", segment)
+			}
+			current = current.Index(idx)
+		case reflect.Struct:
+			field, ok := fieldByTag(current, segment)
+			if !ok {
+				return nil, fmt.Errorf("jtl: no such field %q", segment)
+			}
+			if !field.CanInterface() {
+				return nil, fmt.Errorf("jtl: field %q is unexported", segment)
+			}
+			current = field
+		default:
+			return nil, fmt.Errorf("jtl: cannot resolve %q against %s", segment, current.Kind())
+		}
+	}
+
+	if !current.IsValid() {
+		return nil, fmt.Errorf("jtl: unresolved path %q", path)
+	}
+	return current.Interface(), nil
+}
+
+// fieldByTag finds the struct field on v matching name against its
+// `jtl` tag, falling back to an exact Go field name match.
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("jtl") == name {
+			return v.Field(i), true
+		}
+	}
+	if field := v.FieldByName(name); field.IsValid() {
+		return field, true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MIT
+package jtl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OrtheSnowJames/jtl"
+)
+
+func TestTemplateExecuteWithStruct(t *testing.T) {
+	type User struct {
+		Name string `jtl:"name"`
+	}
+
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="greeting">greeting>$data:name;
+>>>END;`
+
+	tmpl, err := jtl.New("greeting").Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, User{Name: "Ada"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{">>>DOCTYPE=JTL;", ">>>BEGIN;", `class="greeting"`, "greeting>Ada;", ">>>END;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Execute() output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTemplateExecuteWithMap(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="greeting">greeting>$data:user.name;
+>>>END;`
+
+	tmpl, err := jtl.New("greeting").Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	data := map[string]interface{}{
+		"user": map[string]interface{}{"name": "Grace"},
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{">>>DOCTYPE=JTL;", ">>>BEGIN;", `class="greeting"`, "greeting>Grace;", ">>>END;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Execute() output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTemplateExecutePreservesNesting(t *testing.T) {
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="card">card>Title;
+        >class="body">body>$data:name;
+>>>END;`
+
+	tmpl, err := jtl.New("card").Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, map[string]interface{}{"name": "Ada"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	roundTripped, err := jtl.Parse(buf.String())
+	if err != nil {
+		t.Fatalf("Parse() round-trip error = %v\n%s", err, buf.String())
+	}
+	if len(roundTripped) != 1 {
+		t.Fatalf("len(roundTripped) = %d, want 1 root element", len(roundTripped))
+	}
+	children, _ := roundTripped[0].(map[string]interface{})["children"].([]interface{})
+	if len(children) != 1 {
+		t.Fatalf("len(children) = %d, want 1 nested child, got flattened output instead:\n%s", len(children), buf.String())
+	}
+	child, _ := children[0].(map[string]interface{})
+	if child["Content"] != "Ada" {
+		t.Errorf(`child["Content"] = %v, want "Ada"`, child["Content"])
+	}
+}
+
+func TestTemplateExecuteWithUnexportedFieldErrors(t *testing.T) {
+	type User struct {
+		name string `jtl:"name"` //lint:ignore U1000 exercised via $data: below
+	}
+
+	input := `>>>DOCTYPE=JTL;
+>>>BEGIN;
+    >class="greeting">greeting>$data:name;
+>>>END;`
+
+	tmpl, err := jtl.New("greeting").Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf strings.Builder
+	err = tmpl.Execute(&buf, User{name: "Ada"})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want an error for an unexported field")
+	}
+	if !strings.Contains(err.Error(), "unexported") {
+		t.Errorf("Execute() error = %v, want it to mention the field is unexported", err)
+	}
+}